@@ -0,0 +1,26 @@
+package oss
+
+import (
+	"context"
+	"time"
+)
+
+// Credentials are backend-agnostic short- or long-lived credentials
+// returned by a CredentialProvider.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Expires is the zero time if the credentials don't expire.
+	Expires time.Time
+}
+
+// CredentialProvider lets callers plug in a custom credential source (an
+// internal secrets manager, a Vault dynamic secret, ...) instead of the
+// static keys / instance metadata / AssumeRole / web-identity chains a
+// backend supports out of the box. Set it on the relevant provider config
+// (e.g. S3.CredentialProvider) to take priority over every other
+// credential field.
+type CredentialProvider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
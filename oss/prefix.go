@@ -0,0 +1,119 @@
+package oss
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// prefixedStorage wraps an OSS backend and transparently prepends prefix to
+// every key, stripping it back off again on List. This lets multiple
+// tenants share one bucket under different roots without any
+// backend-specific configuration.
+type prefixedStorage struct {
+	inner  OSS
+	prefix string
+}
+
+// WithPrefix returns an OSS that behaves like inner, except every key is
+// rooted under prefix. Callers of the returned OSS see unprefixed keys;
+// the prefix is an implementation detail of how inner stores them.
+func WithPrefix(inner OSS, prefix string) OSS {
+	prefix = strings.Trim(prefix, "/")
+	return &prefixedStorage{
+		inner:  inner,
+		prefix: prefix,
+	}
+}
+
+func (p *prefixedStorage) withPrefix(key string) string {
+	if p.prefix == "" {
+		return key
+	}
+	return p.prefix + "/" + strings.TrimPrefix(key, "/")
+}
+
+func (p *prefixedStorage) Save(key string, data []byte) error {
+	return p.inner.Save(p.withPrefix(key), data)
+}
+
+func (p *prefixedStorage) SaveContext(ctx context.Context, key string, data []byte) error {
+	return p.inner.SaveContext(ctx, p.withPrefix(key), data)
+}
+
+func (p *prefixedStorage) SaveWithOptions(key string, data []byte, opts PutOptions) error {
+	return p.inner.SaveWithOptions(p.withPrefix(key), data, opts)
+}
+
+func (p *prefixedStorage) SaveStream(key string, r io.Reader) error {
+	return p.inner.SaveStream(p.withPrefix(key), r)
+}
+
+func (p *prefixedStorage) Load(key string) ([]byte, error) {
+	return p.inner.Load(p.withPrefix(key))
+}
+
+func (p *prefixedStorage) LoadContext(ctx context.Context, key string) ([]byte, error) {
+	return p.inner.LoadContext(ctx, p.withPrefix(key))
+}
+
+func (p *prefixedStorage) LoadStream(key string) (io.ReadCloser, error) {
+	return p.inner.LoadStream(p.withPrefix(key))
+}
+
+func (p *prefixedStorage) Exists(key string) (bool, error) {
+	return p.inner.Exists(p.withPrefix(key))
+}
+
+func (p *prefixedStorage) State(key string) (OSSState, error) {
+	return p.inner.State(p.withPrefix(key))
+}
+
+func (p *prefixedStorage) Delete(key string) error {
+	return p.inner.Delete(p.withPrefix(key))
+}
+
+func (p *prefixedStorage) DeleteMany(keys []string) (map[string]error, error) {
+	prefixed := make([]string, len(keys))
+	unprefixed := make(map[string]string, len(keys))
+	for i, key := range keys {
+		prefixedKey := p.withPrefix(key)
+		prefixed[i] = prefixedKey
+		unprefixed[prefixedKey] = key
+	}
+
+	result, err := p.inner.DeleteMany(prefixed)
+	if result == nil {
+		return result, err
+	}
+	unwrapped := make(map[string]error, len(result))
+	for key, keyErr := range result {
+		unwrapped[unprefixed[key]] = keyErr
+	}
+	return unwrapped, err
+}
+
+func (p *prefixedStorage) DeletePrefix(prefix string) error {
+	return p.inner.DeletePrefix(p.withPrefix(prefix))
+}
+
+func (p *prefixedStorage) List(prefix string) ([]OSSPath, error) {
+	paths, err := p.inner.List(p.withPrefix(prefix))
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func (p *prefixedStorage) Presign(key string, method HTTPMethod, expiry time.Duration) (string, error) {
+	return p.inner.Presign(p.withPrefix(key), method, expiry)
+}
+
+func (p *prefixedStorage) PresignWithOptions(key string, method HTTPMethod, expiry time.Duration, opts PresignOptions) (string, error) {
+	return p.inner.PresignWithOptions(p.withPrefix(key), method, expiry, opts)
+}
+
+func (p *prefixedStorage) Type() string {
+	return p.inner.Type()
+}
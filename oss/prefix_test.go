@@ -0,0 +1,236 @@
+package oss
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errMemObjectNotFound = errors.New("oss: object not found")
+
+// memStorage is a minimal in-memory OSS used to exercise WithPrefix without
+// depending on any real backend.
+type memStorage struct {
+	objects map[string][]byte
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{objects: map[string][]byte{}}
+}
+
+func (m *memStorage) Save(key string, data []byte) error {
+	m.objects[key] = data
+	return nil
+}
+
+func (m *memStorage) SaveContext(_ context.Context, key string, data []byte) error {
+	return m.Save(key, data)
+}
+
+func (m *memStorage) SaveWithOptions(key string, data []byte, _ PutOptions) error {
+	return m.Save(key, data)
+}
+
+func (m *memStorage) SaveStream(key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return m.Save(key, data)
+}
+
+func (m *memStorage) Load(key string) ([]byte, error) {
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, errMemObjectNotFound
+	}
+	return data, nil
+}
+
+func (m *memStorage) LoadContext(_ context.Context, key string) ([]byte, error) {
+	return m.Load(key)
+}
+
+func (m *memStorage) LoadStream(key string) (io.ReadCloser, error) {
+	data, err := m.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memStorage) Exists(key string) (bool, error) {
+	_, ok := m.objects[key]
+	return ok, nil
+}
+
+func (m *memStorage) State(key string) (OSSState, error) {
+	data, ok := m.objects[key]
+	if !ok {
+		return OSSState{}, errMemObjectNotFound
+	}
+	return OSSState{Size: int64(len(data))}, nil
+}
+
+func (m *memStorage) Delete(key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memStorage) DeleteMany(keys []string) (map[string]error, error) {
+	result := make(map[string]error)
+	for _, key := range keys {
+		delete(m.objects, key)
+	}
+	return result, nil
+}
+
+func (m *memStorage) DeletePrefix(prefix string) error {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	for key := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.objects, key)
+		}
+	}
+	return nil
+}
+
+func (m *memStorage) List(prefix string) ([]OSSPath, error) {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	var paths []OSSPath
+	for key := range m.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		paths = append(paths, OSSPath{Path: strings.TrimPrefix(key, prefix), IsDir: false})
+	}
+	sort.Slice(paths, func(i, j int) bool { return paths[i].Path < paths[j].Path })
+	return paths, nil
+}
+
+func (m *memStorage) Presign(key string, method HTTPMethod, expiry time.Duration) (string, error) {
+	return m.PresignWithOptions(key, method, expiry, PresignOptions{})
+}
+
+func (m *memStorage) PresignWithOptions(key string, method HTTPMethod, _ time.Duration, _ PresignOptions) (string, error) {
+	return "mem://" + string(method) + "/" + key, nil
+}
+
+func (m *memStorage) Type() string {
+	return "mem"
+}
+
+func TestWithPrefixIsolatesKeys(t *testing.T) {
+	inner := newMemStorage()
+	a := WithPrefix(inner, "tenant-a")
+	b := WithPrefix(inner, "tenant-b")
+
+	assert.NoError(t, a.Save("docs/readme", []byte("a")))
+	assert.NoError(t, b.Save("docs/readme", []byte("b")))
+
+	got, err := a.Load("docs/readme")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("a"), got)
+
+	got, err = b.Load("docs/readme")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("b"), got)
+
+	// the underlying backend really does store both copies under distinct keys
+	assert.Equal(t, []byte("a"), inner.objects["tenant-a/docs/readme"])
+	assert.Equal(t, []byte("b"), inner.objects["tenant-b/docs/readme"])
+}
+
+func TestWithPrefixContextVariants(t *testing.T) {
+	inner := newMemStorage()
+	wrapped := WithPrefix(inner, "tenant-a")
+
+	assert.NoError(t, wrapped.SaveContext(context.Background(), "k", []byte("v")))
+	assert.Equal(t, []byte("v"), inner.objects["tenant-a/k"])
+
+	got, err := wrapped.LoadContext(context.Background(), "k")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v"), got)
+}
+
+func TestWithPrefixList(t *testing.T) {
+	inner := newMemStorage()
+	wrapped := WithPrefix(inner, "tenant-a")
+
+	assert.NoError(t, wrapped.Save("dir/one", []byte("1")))
+	assert.NoError(t, wrapped.Save("dir/two", []byte("2")))
+
+	paths, err := wrapped.List("dir")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(paths))
+	assert.Equal(t, "one", paths[0].Path)
+	assert.Equal(t, "two", paths[1].Path)
+}
+
+func TestWithPrefixDeletePrefix(t *testing.T) {
+	inner := newMemStorage()
+	a := WithPrefix(inner, "tenant-a")
+	b := WithPrefix(inner, "tenant-b")
+
+	assert.NoError(t, a.Save("dir/one", []byte("1")))
+	assert.NoError(t, a.Save("dir/two", []byte("2")))
+	assert.NoError(t, b.Save("dir/one", []byte("1")))
+
+	assert.NoError(t, a.DeletePrefix("dir"))
+
+	paths, err := a.List("dir")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(paths))
+
+	// tenant-b's copy under the same unprefixed key is untouched
+	exists, err := b.Exists("dir/one")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestWithPrefixDeleteMany(t *testing.T) {
+	inner := newMemStorage()
+	wrapped := WithPrefix(inner, "tenant-a")
+
+	assert.NoError(t, wrapped.Save("one", []byte("1")))
+	assert.NoError(t, wrapped.Save("two", []byte("2")))
+
+	deleteErrs, err := wrapped.DeleteMany([]string{"one", "two"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(deleteErrs))
+
+	exists, err := wrapped.Exists("one")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestWithPrefixExistsAndDelete(t *testing.T) {
+	inner := newMemStorage()
+	wrapped := WithPrefix(inner, "tenant-a")
+
+	assert.NoError(t, wrapped.Save("k", []byte("v")))
+
+	exists, err := wrapped.Exists("k")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.NoError(t, wrapped.Delete("k"))
+
+	exists, err = wrapped.Exists("k")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	_, exists = inner.objects["tenant-a/k"]
+	assert.False(t, exists)
+}
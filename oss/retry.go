@@ -0,0 +1,88 @@
+package oss
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures the backoff behavior of the backends that call
+// Retry (S3 and Volcengine TOS). Passed to Retry as is, a zero value means
+// "don't retry"; backends resolve it through ResolveRetryPolicy, so
+// DefaultRetryPolicy is used whenever OSSArgs.Retry isn't set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first one.
+	// MaxAttempts == 1 disables retrying; ResolveRetryPolicy replaces
+	// MaxAttempts <= 0 with DefaultRetryPolicy.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0-1) of the computed backoff randomized away,
+	// to avoid every caller retrying in lockstep.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is used by backends when the caller didn't configure
+// OSSArgs.Retry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Jitter:         0.2,
+}
+
+// ResolveRetryPolicy returns policy if it specifies at least one attempt,
+// otherwise DefaultRetryPolicy. Backends call this once, in their
+// constructor, so a zero-value OSSArgs.Retry falls back to sane defaults
+// instead of disabling retries outright.
+func ResolveRetryPolicy(policy RetryPolicy) RetryPolicy {
+	if policy.MaxAttempts > 0 {
+		return policy
+	}
+	return DefaultRetryPolicy
+}
+
+// backoff returns the delay before attempt (1-indexed: the delay before the
+// 2nd attempt, 3rd attempt, ...), with jitter applied.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(2, float64(attempt-1))
+	if max := float64(p.MaxBackoff); max > 0 && d > max {
+		d = max
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// Retry runs fn, retrying per policy while isRetryable(err) is true and ctx
+// hasn't been cancelled. The S3 and Volcengine TOS backends share this loop;
+// each passes its own isRetryable for its SlowDown/TooManyRequests/5xx
+// classification.
+func Retry(ctx context.Context, policy RetryPolicy, isRetryable func(error) bool, fn func() error) error {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if attempt == maxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(policy.backoff(attempt)):
+		}
+	}
+	return err
+}
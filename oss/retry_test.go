@@ -0,0 +1,78 @@
+package oss
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errRetryable = errors.New("retryable")
+var errFatal = errors.New("fatal")
+
+func alwaysRetryable(err error) bool {
+	return errors.Is(err, errRetryable)
+}
+
+func fastPolicy(maxAttempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+}
+
+func TestRetrySucceedsAfterTransientFailures(t *testing.T) {
+	attempts := 0
+	err := Retry(context.Background(), fastPolicy(5), alwaysRetryable, func() error {
+		attempts++
+		if attempts < 3 {
+			return errRetryable
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryStopsOnNonRetryableError(t *testing.T) {
+	attempts := 0
+	err := Retry(context.Background(), fastPolicy(5), alwaysRetryable, func() error {
+		attempts++
+		return errFatal
+	})
+	assert.Equal(t, errFatal, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	attempts := 0
+	err := Retry(context.Background(), fastPolicy(3), alwaysRetryable, func() error {
+		attempts++
+		return errRetryable
+	})
+	assert.Equal(t, errRetryable, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryStopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err := Retry(ctx, fastPolicy(5), alwaysRetryable, func() error {
+		attempts++
+		return errRetryable
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestResolveRetryPolicyFallsBackToDefault(t *testing.T) {
+	assert.Equal(t, DefaultRetryPolicy, ResolveRetryPolicy(RetryPolicy{}))
+
+	custom := RetryPolicy{MaxAttempts: 7, InitialBackoff: time.Second}
+	assert.Equal(t, custom, ResolveRetryPolicy(custom))
+}
@@ -4,21 +4,39 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/aws/smithy-go"
 	"github.com/langgenius/dify-cloud-kit/oss"
 )
 
+// defaultUploadPartSize and defaultUploadConcurrency mirror the
+// aws-sdk-go-v2 manager.Uploader defaults and are used when the caller
+// doesn't configure them explicitly.
+const (
+	defaultUploadPartSize    = manager.DefaultUploadPartSize
+	defaultUploadConcurrency = manager.DefaultUploadConcurrency
+)
+
 type S3Storage struct {
-	bucket string
-	client *s3.Client
+	bucket            string
+	client            *s3.Client
+	uploadPartSize    int64
+	uploadConcurrency int
+	retryPolicy       oss.RetryPolicy
 }
 
 func NewS3Storage(args oss.OSSArgs) (oss.OSS, error) {
@@ -44,12 +62,24 @@ func NewS3Storage(args oss.OSSArgs) (oss.OSS, error) {
 	var client *s3.Client
 
 	if useAws {
-		if (ak == "" && sk == "") || useIamRole {
+		hasPluggableCreds := args.S3.CredentialProvider != nil || args.S3.AssumeRoleARN != "" || args.S3.WebIdentityTokenFile != ""
+
+		switch {
+		case hasPluggableCreds:
+			cfg, err = config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
+			if err == nil {
+				var credProvider aws.CredentialsProvider
+				credProvider, err = resolveCredentialsProvider(cfg, args.S3)
+				if err == nil && credProvider != nil {
+					cfg.Credentials = aws.NewCredentialsCache(credProvider)
+				}
+			}
+		case (ak == "" && sk == "") || useIamRole:
 			cfg, err = config.LoadDefaultConfig(
 				context.TODO(),
 				config.WithRegion(region),
 			)
-		} else {
+		default:
 			// 处理签名版本和凭证
 			var credProvider aws.CredentialsProvider
 			if strings.ToLower(signatureVersion) == "unsigned" {
@@ -79,10 +109,17 @@ func NewS3Storage(args oss.OSSArgs) (oss.OSS, error) {
 			options.UsePathStyle = usePathStyle
 		})
 	} else {
+		if args.S3.AssumeRoleARN != "" || args.S3.WebIdentityTokenFile != "" {
+			return nil, oss.ErrArgumentInvalid.WithDetail("AssumeRoleARN/WebIdentityTokenFile require UseAws and a real AWS STS endpoint; set CredentialProvider instead for non-AWS S3-compatible endpoints")
+		}
+
 		var credProvider aws.CredentialsProvider
-		if strings.ToLower(signatureVersion) == "unsigned" {
+		switch {
+		case args.S3.CredentialProvider != nil:
+			credProvider = credentialProviderAdapter{provider: args.S3.CredentialProvider}
+		case strings.ToLower(signatureVersion) == "unsigned":
 			credProvider = aws.AnonymousCredentials{}
-		} else {
+		default:
 			credProvider = credentials.NewStaticCredentialsProvider(ak, sk, "")
 		}
 		client = s3.New(s3.Options{
@@ -120,7 +157,106 @@ func NewS3Storage(args oss.OSSArgs) (oss.OSS, error) {
 			}
 		}
 	}
-	return &S3Storage{bucket: bucket, client: client}, nil
+	partSize := int64(defaultUploadPartSize)
+	if args.S3.UploadPartSizeMB > 0 {
+		partSize = int64(args.S3.UploadPartSizeMB) * 1024 * 1024
+	}
+	concurrency := defaultUploadConcurrency
+	if args.S3.UploadConcurrency > 0 {
+		concurrency = args.S3.UploadConcurrency
+	}
+
+	return &S3Storage{
+		bucket:            bucket,
+		client:            client,
+		uploadPartSize:    partSize,
+		uploadConcurrency: concurrency,
+		retryPolicy:       oss.ResolveRetryPolicy(args.Retry),
+	}, nil
+}
+
+// isRetryableError classifies the errors worth retrying: S3 throttling
+// (SlowDown, ServiceUnavailable, RequestTimeout) and generic 5xx responses.
+// 4xx errors (NoSuchKey, AccessDenied, ...) are never retried.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "SlowDown", "ServiceUnavailable", "RequestTimeout", "InternalError":
+			return true
+		}
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode() >= 500 || respErr.HTTPStatusCode() == 429
+	}
+	return false
+}
+
+// credentialProviderAdapter adapts an oss.CredentialProvider, which is
+// backend-agnostic, to the aws.CredentialsProvider interface the SDK
+// expects.
+type credentialProviderAdapter struct {
+	provider oss.CredentialProvider
+}
+
+func (a credentialProviderAdapter) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := a.provider.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	return aws.Credentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		CanExpire:       !creds.Expires.IsZero(),
+		Expires:         creds.Expires,
+	}, nil
+}
+
+// resolveCredentialsProvider builds the aws.CredentialsProvider for the
+// "useAws" path, honoring (in priority order) a caller-supplied
+// oss.CredentialProvider, web-identity federation (EKS IRSA / GKE Workload
+// Identity) into AssumeRoleARN, plain AssumeRole, then falling back to nil
+// so the caller keeps using its existing static-key/IAM-role/default-chain
+// logic.
+func resolveCredentialsProvider(cfg aws.Config, s3Args *oss.S3) (aws.CredentialsProvider, error) {
+	if s3Args.CredentialProvider != nil {
+		return credentialProviderAdapter{provider: s3Args.CredentialProvider}, nil
+	}
+
+	stsClient := sts.NewFromConfig(cfg)
+
+	if s3Args.WebIdentityTokenFile != "" {
+		roleSessionName := s3Args.AssumeRoleSessionName
+		if roleSessionName == "" {
+			roleSessionName = "dify-cloud-kit"
+		}
+		return stscreds.NewWebIdentityRoleProvider(
+			stsClient,
+			s3Args.AssumeRoleARN,
+			stscreds.IdentityTokenFile(s3Args.WebIdentityTokenFile),
+			func(o *stscreds.WebIdentityRoleOptions) {
+				o.RoleSessionName = roleSessionName
+			},
+		), nil
+	}
+
+	if s3Args.AssumeRoleARN != "" {
+		return stscreds.NewAssumeRoleProvider(stsClient, s3Args.AssumeRoleARN, func(o *stscreds.AssumeRoleOptions) {
+			if s3Args.AssumeRoleSessionName != "" {
+				o.RoleSessionName = s3Args.AssumeRoleSessionName
+			}
+			if s3Args.AssumeRoleExternalID != "" {
+				o.ExternalID = aws.String(s3Args.AssumeRoleExternalID)
+			}
+		}), nil
+	}
+
+	return nil, nil
 }
 
 func normalizeSignatureVersion(version string) string {
@@ -135,43 +271,387 @@ func normalizeSignatureVersion(version string) string {
 }
 
 func (s *S3Storage) Save(key string, data []byte) error {
-	_, err := s.client.PutObject(context.TODO(), &s3.PutObjectInput{
+	return s.SaveContext(context.Background(), key, data)
+}
+
+// SaveContext behaves like Save but lets the caller bound the upload with
+// ctx and retries transient failures (throttling, 5xx) per the backend's
+// retry policy.
+func (s *S3Storage) SaveContext(ctx context.Context, key string, data []byte) error {
+	return oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+			Body:   bytes.NewReader(data),
+		})
+		return err
+	})
+}
+
+// SaveWithOptions behaves like Save but additionally lets the caller set
+// server-side encryption (SSE-S3, SSE-KMS or SSE-C), content type, cache
+// control and user metadata on the object.
+func (s *S3Storage) SaveWithOptions(key string, data []byte, opts oss.PutOptions) error {
+	return s.SaveWithOptionsContext(context.Background(), key, data, opts)
+}
+
+// SaveWithOptionsContext behaves like SaveWithOptions but lets the caller
+// bound the upload with ctx and retries transient failures (throttling,
+// 5xx) per the backend's retry policy.
+func (s *S3Storage) SaveWithOptionsContext(ctx context.Context, key string, data []byte, opts oss.PutOptions) error {
+	return oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		input := &s3.PutObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+			Body:   bytes.NewReader(data),
+		}
+		applyPutOptions(input, opts)
+		_, err := s.client.PutObject(ctx, input)
+		return err
+	})
+}
+
+// applyPutOptions maps the backend-agnostic oss.PutOptions onto a
+// s3.PutObjectInput.
+func applyPutOptions(input *s3.PutObjectInput, opts oss.PutOptions) {
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	if opts.CacheControl != "" {
+		input.CacheControl = aws.String(opts.CacheControl)
+	}
+	if len(opts.Metadata) > 0 {
+		input.Metadata = opts.Metadata
+	}
+	if opts.ServerSideEncryption != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(opts.ServerSideEncryption)
+	}
+	if opts.EncryptionKeyID != "" {
+		input.SSEKMSKeyId = aws.String(opts.EncryptionKeyID)
+	}
+	if opts.SSECustomerAlgorithm != "" {
+		input.SSECustomerAlgorithm = aws.String(opts.SSECustomerAlgorithm)
+		input.SSECustomerKey = aws.String(opts.SSECustomerKey)
+		input.SSECustomerKeyMD5 = aws.String(opts.SSECustomerKeyMD5)
+	}
+}
+
+// SaveStream uploads r to key using the multipart upload manager so the
+// caller never has to buffer the whole object in memory. Part size and
+// concurrency are taken from the S3 args passed to NewS3Storage.
+func (s *S3Storage) SaveStream(key string, r io.Reader) error {
+	return s.SaveStreamContext(context.Background(), key, r)
+}
+
+// SaveStreamContext behaves like SaveStream but lets the caller bound the
+// upload with ctx. It doesn't go through oss.Retry: r is consumed as the
+// upload progresses, so once any bytes have been read from it a retry of
+// the whole call could only resend a truncated object. Retrying the
+// individual PutObject/UploadPart requests the manager issues per part is
+// left to the S3 client's own configured retryer.
+func (s *S3Storage) SaveStreamContext(ctx context.Context, key string, r io.Reader) error {
+	uploader := manager.NewUploader(s.client, func(u *manager.Uploader) {
+		u.PartSize = s.uploadPartSize
+		u.Concurrency = s.uploadConcurrency
+	})
+	_, err := uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
-		Body:   bytes.NewReader(data),
+		Body:   r,
 	})
 	return err
 }
 
-func (s *S3Storage) Load(key string) ([]byte, error) {
-	resp, err := s.client.GetObject(context.TODO(), &s3.GetObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(key),
+// LoadStream returns the object body as a streaming reader. The caller is
+// responsible for closing it.
+func (s *S3Storage) LoadStream(key string) (io.ReadCloser, error) {
+	return s.LoadStreamContext(context.Background(), key)
+}
+
+// LoadStreamContext behaves like LoadStream but lets the caller bound the
+// request with ctx. Only opening the stream is retried through oss.Retry;
+// once the caller starts reading resp.Body a mid-stream failure surfaces as
+// a read error rather than a transparent retry, since replaying already
+// consumed bytes isn't possible without buffering the whole object.
+func (s *S3Storage) LoadStreamContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		resp, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return err
+		}
+		body = resp.Body
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
+	return body, nil
+}
 
-	return io.ReadAll(resp.Body)
+func (s *S3Storage) Load(key string) ([]byte, error) {
+	return s.LoadContext(context.Background(), key)
+}
+
+// LoadContext behaves like Load but lets the caller bound the download with
+// ctx and retries transient failures (throttling, 5xx) per the backend's
+// retry policy.
+func (s *S3Storage) LoadContext(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		resp, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		data, err = io.ReadAll(resp.Body)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 func (s *S3Storage) Exists(key string) (bool, error) {
-	_, err := s.client.HeadObject(context.TODO(), &s3.HeadObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(key),
+	return s.ExistsContext(context.Background(), key)
+}
+
+// ExistsContext behaves like Exists but lets the caller bound the request
+// with ctx and retries transient failures (throttling, 5xx) per the
+// backend's retry policy.
+func (s *S3Storage) ExistsContext(ctx context.Context, key string) (bool, error) {
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+		})
+		return err
 	})
 	return err == nil, nil
 }
 
 func (s *S3Storage) Delete(key string) error {
-	_, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext behaves like Delete but lets the caller bound the request
+// with ctx and retries transient failures (throttling, 5xx) per the
+// backend's retry policy.
+func (s *S3Storage) DeleteContext(ctx context.Context, key string) error {
+	return oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+		})
+		return err
+	})
+}
+
+// DeleteMany deletes all of keys in batches of up to 1000 using
+// S3's DeleteObjects API, returning a per-key error for any object that
+// failed to delete. A nil overall error means every batch request
+// succeeded, though individual keys may still be present in the result map.
+func (s *S3Storage) DeleteMany(keys []string) (map[string]error, error) {
+	return s.DeleteManyContext(context.Background(), keys)
+}
+
+// DeleteManyContext behaves like DeleteMany but lets the caller bound the
+// request with ctx and retries a batch's DeleteObjects call on transient
+// failures (throttling, 5xx) per the backend's retry policy.
+func (s *S3Storage) DeleteManyContext(ctx context.Context, keys []string) (map[string]error, error) {
+	result := make(map[string]error)
+
+	for _, input := range buildDeleteObjectsBatches(s.bucket, keys) {
+		var resp *s3.DeleteObjectsOutput
+		err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+			var deleteErr error
+			resp, deleteErr = s.client.DeleteObjects(ctx, input)
+			return deleteErr
+		})
+		if err != nil {
+			return result, err
+		}
+		for _, objErr := range resp.Errors {
+			result[aws.ToString(objErr.Key)] = errors.New(aws.ToString(objErr.Message))
+		}
+	}
+
+	return result, nil
+}
+
+// buildDeleteObjectsBatches splits keys into groups of at most 1000 (the
+// limit DeleteObjects accepts in a single call) and builds the
+// s3.DeleteObjectsInput for each group.
+func buildDeleteObjectsBatches(bucket string, keys []string) []*s3.DeleteObjectsInput {
+	const batchSize = 1000
+
+	var batches []*s3.DeleteObjectsInput
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := keys[start:end]
+
+		objects := make([]types.ObjectIdentifier, len(batch))
+		for i, key := range batch {
+			objects[i] = types.ObjectIdentifier{Key: aws.String(key)}
+		}
+
+		batches = append(batches, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{
+				Objects: objects,
+				Quiet:   true,
+			},
+		})
+	}
+
+	return batches
+}
+
+// DeletePrefix removes every object whose key starts with prefix.
+func (s *S3Storage) DeletePrefix(prefix string) error {
+	return s.DeletePrefixContext(context.Background(), prefix)
+}
+
+// DeletePrefixContext behaves like DeletePrefix but lets the caller bound
+// the request with ctx.
+func (s *S3Storage) DeletePrefixContext(ctx context.Context, prefix string) error {
+	var keys []string
+	input := &s3.ListObjectsV2Input{
 		Bucket: aws.String(s.bucket),
-		Key:    aws.String(key),
+		Prefix: aws.String(prefix),
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, input)
+	for paginator.HasMorePages() {
+		var page *s3.ListObjectsV2Output
+		err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+			var listErr error
+			page, listErr = paginator.NextPage(ctx)
+			return listErr
+		})
+		if err != nil {
+			return err
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	deleteErrs, err := s.DeleteManyContext(ctx, keys)
+	if err != nil {
+		return err
+	}
+	if len(deleteErrs) == 0 {
+		return nil
+	}
+
+	failed := make([]string, 0, len(deleteErrs))
+	for key, deleteErr := range deleteErrs {
+		failed = append(failed, key+": "+deleteErr.Error())
+	}
+	sort.Strings(failed)
+	return fmt.Errorf("failed to delete %d of %d objects under prefix %q: %s", len(deleteErrs), len(keys), prefix, strings.Join(failed, "; "))
+}
+
+// Presign returns a time-limited URL for GET or PUT access to key without
+// going through the app server. See PresignWithOptions to bind a content
+// type or override response headers on a presigned GET.
+func (s *S3Storage) Presign(key string, method oss.HTTPMethod, expiry time.Duration) (string, error) {
+	return s.PresignWithOptions(key, method, expiry, oss.PresignOptions{})
+}
+
+// PresignWithOptions behaves like Presign but additionally lets the caller
+// bind a content type (PUT) or override response headers (GET).
+func (s *S3Storage) PresignWithOptions(key string, method oss.HTTPMethod, expiry time.Duration, opts oss.PresignOptions) (string, error) {
+	return s.PresignWithOptionsContext(context.Background(), key, method, expiry, opts)
+}
+
+// PresignWithOptionsContext behaves like PresignWithOptions but lets the
+// caller bound the request with ctx and retries transient failures
+// (throttling, 5xx) per the backend's retry policy.
+func (s *S3Storage) PresignWithOptionsContext(ctx context.Context, key string, method oss.HTTPMethod, expiry time.Duration, opts oss.PresignOptions) (string, error) {
+	presignClient := s3.NewPresignClient(s.client)
+
+	var url string
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		switch method {
+		case oss.HTTPMethodGet:
+			input := buildPresignGetObjectInput(s.bucket, key, opts)
+			req, err := presignClient.PresignGetObject(ctx, input, s3.WithPresignExpires(expiry))
+			if err != nil {
+				return err
+			}
+			url = req.URL
+			return nil
+		case oss.HTTPMethodPut:
+			input := buildPresignPutObjectInput(s.bucket, key, opts)
+			req, err := presignClient.PresignPutObject(ctx, input, s3.WithPresignExpires(expiry))
+			if err != nil {
+				return err
+			}
+			url = req.URL
+			return nil
+		default:
+			return oss.ErrArgumentInvalid.WithDetail("unsupported presign method: " + string(method))
+		}
 	})
-	return err
+	if err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+// buildPresignGetObjectInput maps the backend-agnostic oss.PresignOptions
+// onto a s3.GetObjectInput for a presigned GET.
+func buildPresignGetObjectInput(bucket, key string, opts oss.PresignOptions) *s3.GetObjectInput {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if opts.ResponseContentType != "" {
+		input.ResponseContentType = aws.String(opts.ResponseContentType)
+	}
+	if opts.ResponseContentDisposition != "" {
+		input.ResponseContentDisposition = aws.String(opts.ResponseContentDisposition)
+	}
+	return input
+}
+
+// buildPresignPutObjectInput maps the backend-agnostic oss.PresignOptions
+// onto a s3.PutObjectInput for a presigned PUT.
+func buildPresignPutObjectInput(bucket, key string, opts oss.PresignOptions) *s3.PutObjectInput {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	return input
 }
 
 func (s *S3Storage) List(prefix string) ([]oss.OSSPath, error) {
+	return s.ListContext(context.Background(), prefix)
+}
+
+// ListContext behaves like List but lets the caller bound the request with
+// ctx and retries each page's transient failures (throttling, 5xx) per the
+// backend's retry policy.
+func (s *S3Storage) ListContext(ctx context.Context, prefix string) ([]oss.OSSPath, error) {
 	// append a slash to the prefix if it doesn't end with one
 	if !strings.HasSuffix(prefix, "/") {
 		prefix = prefix + "/"
@@ -185,7 +665,12 @@ func (s *S3Storage) List(prefix string) ([]oss.OSSPath, error) {
 
 	paginator := s3.NewListObjectsV2Paginator(s.client, input)
 	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
+		var page *s3.ListObjectsV2Output
+		err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+			var pageErr error
+			page, pageErr = paginator.NextPage(ctx)
+			return pageErr
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -205,9 +690,21 @@ func (s *S3Storage) List(prefix string) ([]oss.OSSPath, error) {
 }
 
 func (s *S3Storage) State(key string) (oss.OSSState, error) {
-	resp, err := s.client.HeadObject(context.TODO(), &s3.HeadObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(key),
+	return s.StateContext(context.Background(), key)
+}
+
+// StateContext behaves like State but lets the caller bound the request
+// with ctx and retries transient failures (throttling, 5xx) per the
+// backend's retry policy.
+func (s *S3Storage) StateContext(ctx context.Context, key string) (oss.OSSState, error) {
+	var resp *s3.HeadObjectOutput
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		var headErr error
+		resp, headErr = s.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+		})
+		return headErr
 	})
 	if err != nil {
 		return oss.OSSState{}, err
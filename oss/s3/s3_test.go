@@ -0,0 +1,234 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/langgenius/dify-cloud-kit/oss"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCredentialProvider struct {
+	creds oss.Credentials
+	err   error
+}
+
+func (f fakeCredentialProvider) Retrieve(_ context.Context) (oss.Credentials, error) {
+	return f.creds, f.err
+}
+
+func TestResolveCredentialsProviderPrefersCustomProvider(t *testing.T) {
+	fake := fakeCredentialProvider{creds: oss.Credentials{AccessKeyID: "custom-ak", SecretAccessKey: "custom-sk"}}
+	s3Args := &oss.S3{
+		CredentialProvider:   fake,
+		AssumeRoleARN:        "arn:aws:iam::123456789012:role/example",
+		WebIdentityTokenFile: "/var/run/secrets/token",
+	}
+
+	provider, err := resolveCredentialsProvider(aws.Config{}, s3Args)
+	assert.NoError(t, err)
+
+	adapter, ok := provider.(credentialProviderAdapter)
+	assert.True(t, ok, "expected the custom CredentialProvider to take priority")
+
+	creds, err := adapter.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "custom-ak", creds.AccessKeyID)
+}
+
+func TestResolveCredentialsProviderPrefersWebIdentityOverAssumeRole(t *testing.T) {
+	s3Args := &oss.S3{
+		AssumeRoleARN:        "arn:aws:iam::123456789012:role/example",
+		WebIdentityTokenFile: "/var/run/secrets/token",
+	}
+
+	provider, err := resolveCredentialsProvider(aws.Config{}, s3Args)
+	assert.NoError(t, err)
+
+	_, ok := provider.(*stscreds.WebIdentityRoleProvider)
+	assert.True(t, ok, "expected web-identity federation to take priority over AssumeRoleARN")
+}
+
+func TestResolveCredentialsProviderFallsBackToAssumeRole(t *testing.T) {
+	s3Args := &oss.S3{
+		AssumeRoleARN: "arn:aws:iam::123456789012:role/example",
+	}
+
+	provider, err := resolveCredentialsProvider(aws.Config{}, s3Args)
+	assert.NoError(t, err)
+
+	_, ok := provider.(*stscreds.AssumeRoleProvider)
+	assert.True(t, ok, "expected AssumeRoleARN to be honored when no custom provider or web-identity is set")
+}
+
+func TestResolveCredentialsProviderReturnsNilWithNoPluggableCreds(t *testing.T) {
+	provider, err := resolveCredentialsProvider(aws.Config{}, &oss.S3{})
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+}
+
+func TestApplyPutOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts oss.PutOptions
+		want *s3.PutObjectInput
+	}{
+		{
+			name: "empty options leave the input untouched",
+			opts: oss.PutOptions{},
+			want: &s3.PutObjectInput{},
+		},
+		{
+			name: "content type, cache control and metadata",
+			opts: oss.PutOptions{
+				ContentType:  "application/json",
+				CacheControl: "max-age=3600",
+				Metadata:     map[string]string{"owner": "dify"},
+			},
+			want: &s3.PutObjectInput{
+				ContentType:  aws.String("application/json"),
+				CacheControl: aws.String("max-age=3600"),
+				Metadata:     map[string]string{"owner": "dify"},
+			},
+		},
+		{
+			name: "SSE-KMS",
+			opts: oss.PutOptions{
+				ServerSideEncryption: "aws:kms",
+				EncryptionKeyID:      "arn:aws:kms:us-east-1:123456789012:key/example",
+			},
+			want: &s3.PutObjectInput{
+				ServerSideEncryption: types.ServerSideEncryptionAwsKms,
+				SSEKMSKeyId:          aws.String("arn:aws:kms:us-east-1:123456789012:key/example"),
+			},
+		},
+		{
+			name: "SSE-C",
+			opts: oss.PutOptions{
+				SSECustomerAlgorithm: "AES256",
+				SSECustomerKey:       "base64-key",
+				SSECustomerKeyMD5:    "base64-md5",
+			},
+			want: &s3.PutObjectInput{
+				SSECustomerAlgorithm: aws.String("AES256"),
+				SSECustomerKey:       aws.String("base64-key"),
+				SSECustomerKeyMD5:    aws.String("base64-md5"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &s3.PutObjectInput{}
+			applyPutOptions(input, tt.opts)
+			assert.Equal(t, tt.want, input)
+		})
+	}
+}
+
+func TestBuildPresignGetObjectInput(t *testing.T) {
+	tests := []struct {
+		name string
+		opts oss.PresignOptions
+		want *s3.GetObjectInput
+	}{
+		{
+			name: "no response overrides",
+			opts: oss.PresignOptions{},
+			want: &s3.GetObjectInput{
+				Bucket: aws.String("bucket"),
+				Key:    aws.String("key"),
+			},
+		},
+		{
+			name: "response content type and disposition overrides",
+			opts: oss.PresignOptions{
+				ResponseContentType:        "application/pdf",
+				ResponseContentDisposition: "attachment; filename=report.pdf",
+			},
+			want: &s3.GetObjectInput{
+				Bucket:                     aws.String("bucket"),
+				Key:                        aws.String("key"),
+				ResponseContentType:        aws.String("application/pdf"),
+				ResponseContentDisposition: aws.String("attachment; filename=report.pdf"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildPresignGetObjectInput("bucket", "key", tt.opts)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildPresignPutObjectInput(t *testing.T) {
+	tests := []struct {
+		name string
+		opts oss.PresignOptions
+		want *s3.PutObjectInput
+	}{
+		{
+			name: "no content type override",
+			opts: oss.PresignOptions{},
+			want: &s3.PutObjectInput{
+				Bucket: aws.String("bucket"),
+				Key:    aws.String("key"),
+			},
+		},
+		{
+			name: "content type override",
+			opts: oss.PresignOptions{ContentType: "image/png"},
+			want: &s3.PutObjectInput{
+				Bucket:      aws.String("bucket"),
+				Key:         aws.String("key"),
+				ContentType: aws.String("image/png"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildPresignPutObjectInput("bucket", "key", tt.opts)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildDeleteObjectsBatches(t *testing.T) {
+	t.Run("no keys produces no batches", func(t *testing.T) {
+		got := buildDeleteObjectsBatches("bucket", nil)
+		assert.Nil(t, got)
+	})
+
+	t.Run("fewer than 1000 keys produces a single batch", func(t *testing.T) {
+		keys := []string{"a", "b", "c"}
+		got := buildDeleteObjectsBatches("bucket", keys)
+		assert.Len(t, got, 1)
+		assert.Equal(t, aws.String("bucket"), got[0].Bucket)
+		assert.True(t, got[0].Delete.Quiet)
+		assert.Len(t, got[0].Delete.Objects, 3)
+		assert.Equal(t, aws.String("a"), got[0].Delete.Objects[0].Key)
+		assert.Equal(t, aws.String("b"), got[0].Delete.Objects[1].Key)
+		assert.Equal(t, aws.String("c"), got[0].Delete.Objects[2].Key)
+	})
+
+	t.Run("more than 1000 keys splits into multiple batches", func(t *testing.T) {
+		keys := make([]string, 1500)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("key-%d", i)
+		}
+		got := buildDeleteObjectsBatches("bucket", keys)
+		assert.Len(t, got, 2)
+		assert.Len(t, got[0].Delete.Objects, 1000)
+		assert.Len(t, got[1].Delete.Objects, 500)
+		assert.Equal(t, aws.String("key-0"), got[0].Delete.Objects[0].Key)
+		assert.Equal(t, aws.String("key-1000"), got[1].Delete.Objects[0].Key)
+	})
+}
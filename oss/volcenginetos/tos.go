@@ -3,15 +3,26 @@ package volcenginetos
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/langgenius/dify-cloud-kit/oss"
 	"github.com/volcengine/ve-tos-golang-sdk/v2/tos"
 	"io"
 	"strings"
+	"time"
 )
 
+// defaultUploadPartSize is used for multipart uploads via SaveStream when
+// the caller doesn't configure a part size explicitly.
+const defaultUploadPartSize = 16 * 1024 * 1024
+
 type VolcengineTOSStorage struct {
-	bucket string
-	client *tos.ClientV2
+	bucket         string
+	client         *tos.ClientV2
+	uploadPartSize int64
+	retryPolicy    oss.RetryPolicy
 }
 
 func NewVolcengineTOSStorage(args oss.OSSArgs) (oss.OSS, error) {
@@ -30,45 +41,335 @@ func NewVolcengineTOSStorage(args oss.OSSArgs) (oss.OSS, error) {
 	endpoint := args.VolcengineTOS.Endpoint
 	region := args.VolcengineTOS.Region
 
+	var credentials tos.Credentials
+	if args.VolcengineTOS.CredentialProvider != nil {
+		credentials = credentialProviderAdapter{provider: args.VolcengineTOS.CredentialProvider}
+	} else {
+		credentials = tos.NewStaticCredentials(accessKey, secretKey)
+	}
+
 	client, err := tos.NewClientV2(endpoint,
 		tos.WithRegion(region),
-		tos.WithCredentials(tos.NewStaticCredentials(accessKey, secretKey)),
+		tos.WithCredentials(credentials),
 	)
 	if err != nil {
 		return nil, oss.ErrProviderInit.WithError(err)
 	}
+	partSize := int64(defaultUploadPartSize)
+	if args.VolcengineTOS.UploadPartSizeMB > 0 {
+		partSize = int64(args.VolcengineTOS.UploadPartSizeMB) * 1024 * 1024
+	}
+
 	return &VolcengineTOSStorage{
-		bucket: bucket,
-		client: client,
+		bucket:         bucket,
+		client:         client,
+		uploadPartSize: partSize,
+		retryPolicy:    oss.ResolveRetryPolicy(args.Retry),
 	}, nil
 }
 
+// credentialProviderAdapter adapts an oss.CredentialProvider, which is
+// backend-agnostic, to the tos.Credentials interface the TOS client expects
+// of WithCredentials.
+//
+// Unlike S3, this package doesn't offer an AssumeRoleARN/WebIdentityTokenFile
+// equivalent: the bundled TOS SDK has no STS client the way aws-sdk-go-v2
+// does, so there's no in-package way to exchange a role ARN or identity
+// token for temporary credentials. Callers that need that (e.g. Volcengine
+// STS-issued session credentials) should do the exchange themselves and hand
+// the result to VolcengineTOS.CredentialProvider.
+type credentialProviderAdapter struct {
+	provider oss.CredentialProvider
+}
+
+// Credential implements tos.Credentials. The interface has no way to
+// propagate an error, so a failed Retrieve yields a zero-value credential,
+// which simply fails the next request with AccessDenied rather than panic.
+func (a credentialProviderAdapter) Credential() tos.Credential {
+	creds, err := a.provider.Retrieve(context.Background())
+	if err != nil {
+		return tos.Credential{}
+	}
+	return tos.Credential{
+		AccessKeyID:     creds.AccessKeyID,
+		AccessKeySecret: creds.SecretAccessKey,
+		SecurityToken:   creds.SessionToken,
+	}
+}
+
+// isRetryableError classifies the errors worth retrying: TOS throttling
+// (TooManyRequests) and generic 5xx responses. 4xx errors (NoSuchKey,
+// AccessDenied, ...) are never retried.
+func isRetryableError(err error) bool {
+	var tosErr *tos.TosServerError
+	if errors.As(err, &tosErr) {
+		return tosErr.StatusCode >= 500 || tosErr.StatusCode == 429
+	}
+	return false
+}
+
 func (s *VolcengineTOSStorage) Save(key string, data []byte) error {
-	_, err := s.client.PutObjectV2(context.Background(), &tos.PutObjectV2Input{
-		PutObjectBasicInput: tos.PutObjectBasicInput{
+	return s.SaveContext(context.Background(), key, data)
+}
+
+// SaveContext behaves like Save but lets the caller bound the upload with
+// ctx and retries transient failures (throttling, 5xx) per the backend's
+// retry policy.
+func (s *VolcengineTOSStorage) SaveContext(ctx context.Context, key string, data []byte) error {
+	return oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		_, err := s.client.PutObjectV2(ctx, &tos.PutObjectV2Input{
+			PutObjectBasicInput: tos.PutObjectBasicInput{
+				Bucket: s.bucket,
+				Key:    key,
+			},
+			Content: bytes.NewReader(data),
+		})
+		return err
+	})
+}
+
+// SaveWithOptions behaves like Save but additionally lets the caller set
+// server-side encryption (SSE-KMS or customer-provided keys), content type,
+// cache control and user metadata on the object.
+func (s *VolcengineTOSStorage) SaveWithOptions(key string, data []byte, opts oss.PutOptions) error {
+	return s.SaveWithOptionsContext(context.Background(), key, data, opts)
+}
+
+// SaveWithOptionsContext behaves like SaveWithOptions but lets the caller
+// bound the upload with ctx and retries transient failures (throttling,
+// 5xx) per the backend's retry policy.
+func (s *VolcengineTOSStorage) SaveWithOptionsContext(ctx context.Context, key string, data []byte, opts oss.PutOptions) error {
+	return oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		input := &tos.PutObjectV2Input{
+			PutObjectBasicInput: tos.PutObjectBasicInput{
+				Bucket: s.bucket,
+				Key:    key,
+			},
+			Content: bytes.NewReader(data),
+		}
+		applyPutOptions(&input.PutObjectBasicInput, opts)
+		_, err := s.client.PutObjectV2(ctx, input)
+		return err
+	})
+}
+
+// applyPutOptions maps the backend-agnostic oss.PutOptions onto a
+// tos.PutObjectBasicInput, mirroring how S3's own applyPutOptions in
+// oss/s3/s3.go maps the same fields: ServerSideEncryption/EncryptionKeyID
+// select TOS-managed (KMS) encryption, while SSECustomerAlgorithm/Key/KeyMD5
+// carry a caller-supplied key for SSE-C.
+func applyPutOptions(input *tos.PutObjectBasicInput, opts oss.PutOptions) {
+	if opts.ContentType != "" {
+		input.ContentType = opts.ContentType
+	}
+	if opts.CacheControl != "" {
+		input.CacheControl = opts.CacheControl
+	}
+	if len(opts.Metadata) > 0 {
+		input.Meta = opts.Metadata
+	}
+	if opts.ServerSideEncryption != "" {
+		input.ServerSideEncryption = opts.ServerSideEncryption
+	}
+	if opts.EncryptionKeyID != "" {
+		input.SSEKMSKeyID = opts.EncryptionKeyID
+	}
+	if opts.SSECustomerAlgorithm != "" {
+		input.SSECAlgorithm = opts.SSECustomerAlgorithm
+		input.SSECKey = opts.SSECustomerKey
+		input.SSECKeyMD5 = opts.SSECustomerKeyMD5
+	}
+}
+
+// SaveStream uploads r to key using the TOS multipart upload APIs so large
+// objects never need to be fully buffered in memory.
+func (s *VolcengineTOSStorage) SaveStream(key string, r io.Reader) error {
+	return s.SaveStreamContext(context.Background(), key, r)
+}
+
+// SaveStreamContext behaves like SaveStream but lets the caller bound the
+// upload with ctx. Creating the upload, uploading each part and completing
+// it all retry transient failures (throttling, 5xx) per the backend's retry
+// policy — each part is read into buf before it's sent, so resending it is
+// always safe, unlike retrying the call as a whole would be for r itself.
+//
+// TOS's CompleteMultipartUploadV2 rejects an upload with zero parts, so an
+// empty (or already-exhausted) r is special-cased to a direct PutObjectV2
+// instead of going through the multipart APIs at all.
+func (s *VolcengineTOSStorage) SaveStreamContext(ctx context.Context, key string, r io.Reader) error {
+	buf := make([]byte, s.uploadPartSize)
+	n, readErr := io.ReadFull(r, buf)
+	if readErr != nil && readErr != io.ErrUnexpectedEOF && readErr != io.EOF {
+		return readErr
+	}
+	if n == 0 {
+		return oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+			_, err := s.client.PutObjectV2(ctx, &tos.PutObjectV2Input{
+				PutObjectBasicInput: tos.PutObjectBasicInput{
+					Bucket: s.bucket,
+					Key:    key,
+				},
+				Content: bytes.NewReader(nil),
+			})
+			return err
+		})
+	}
+	firstChunk := append([]byte(nil), buf[:n]...)
+	firstChunkIsFinal := readErr == io.EOF || readErr == io.ErrUnexpectedEOF
+
+	var createResp *tos.CreateMultipartUploadV2Output
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		var createErr error
+		createResp, createErr = s.client.CreateMultipartUploadV2(ctx, &tos.CreateMultipartUploadV2Input{
 			Bucket: s.bucket,
 			Key:    key,
-		},
-		Content: bytes.NewReader(data),
+		})
+		return createErr
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	abort := func() {
+		_, _ = s.client.AbortMultipartUpload(ctx, &tos.AbortMultipartUploadInput{
+			Bucket:   s.bucket,
+			Key:      key,
+			UploadID: createResp.UploadID,
+		})
+	}
+
+	uploadPart := func(partNumber int, content []byte) (tos.UploadedPartV2, error) {
+		var partResp *tos.UploadPartV2Output
+		uploadErr := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+			var err error
+			partResp, err = s.client.UploadPartV2(ctx, &tos.UploadPartV2Input{
+				UploadPartBasicInput: tos.UploadPartBasicInput{
+					Bucket:     s.bucket,
+					Key:        key,
+					UploadID:   createResp.UploadID,
+					PartNumber: partNumber,
+				},
+				Content: bytes.NewReader(content),
+			})
+			return err
+		})
+		if uploadErr != nil {
+			return tos.UploadedPartV2{}, uploadErr
+		}
+		return tos.UploadedPartV2{PartNumber: partNumber, ETag: partResp.ETag}, nil
+	}
+
+	var parts []tos.UploadedPartV2
+	partNumber := 1
+	part, uploadErr := uploadPart(partNumber, firstChunk)
+	if uploadErr != nil {
+		abort()
+		return uploadErr
+	}
+	parts = append(parts, part)
+	partNumber++
+
+	for !firstChunkIsFinal {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			part, uploadErr := uploadPart(partNumber, buf[:n])
+			if uploadErr != nil {
+				abort()
+				return uploadErr
+			}
+			parts = append(parts, part)
+			partNumber++
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			abort()
+			return readErr
+		}
+	}
+
+	return oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		_, err := s.client.CompleteMultipartUploadV2(ctx, &tos.CompleteMultipartUploadV2Input{
+			Bucket:   s.bucket,
+			Key:      key,
+			UploadID: createResp.UploadID,
+			Parts:    parts,
+		})
+		return err
+	})
+}
+
+// LoadStream returns the object body as a streaming reader. The caller is
+// responsible for closing it.
+func (s *VolcengineTOSStorage) LoadStream(key string) (io.ReadCloser, error) {
+	return s.LoadStreamContext(context.Background(), key)
+}
+
+// LoadStreamContext behaves like LoadStream but lets the caller bound the
+// request with ctx. Only opening the stream is retried through oss.Retry;
+// once the caller starts reading resp.Content a mid-stream failure surfaces
+// as a read error rather than a transparent retry.
+func (s *VolcengineTOSStorage) LoadStreamContext(ctx context.Context, key string) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		resp, err := s.client.GetObjectV2(ctx, &tos.GetObjectV2Input{
+			Bucket: s.bucket,
+			Key:    key,
+		})
+		if err != nil {
+			return err
+		}
+		body = resp.Content
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
 }
 
 func (s *VolcengineTOSStorage) Load(key string) ([]byte, error) {
-	resp, err := s.client.GetObjectV2(context.Background(), &tos.GetObjectV2Input{
-		Bucket: s.bucket,
-		Key:    key,
+	return s.LoadContext(context.Background(), key)
+}
+
+// LoadContext behaves like Load but lets the caller bound the download with
+// ctx and retries transient failures (throttling, 5xx) per the backend's
+// retry policy.
+func (s *VolcengineTOSStorage) LoadContext(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		resp, err := s.client.GetObjectV2(ctx, &tos.GetObjectV2Input{
+			Bucket: s.bucket,
+			Key:    key,
+		})
+		if err != nil {
+			return err
+		}
+		defer resp.Content.Close()
+		data, err = io.ReadAll(resp.Content)
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
-	return io.ReadAll(resp.Content)
+	return data, nil
 }
 
 func (s *VolcengineTOSStorage) Exists(key string) (bool, error) {
-	_, err := s.client.HeadObjectV2(context.Background(), &tos.HeadObjectV2Input{
-		Bucket: s.bucket,
-		Key:    key,
+	return s.ExistsContext(context.Background(), key)
+}
+
+// ExistsContext behaves like Exists but lets the caller bound the request
+// with ctx and retries transient failures (throttling, 5xx) per the
+// backend's retry policy.
+func (s *VolcengineTOSStorage) ExistsContext(ctx context.Context, key string) (bool, error) {
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		_, err := s.client.HeadObjectV2(ctx, &tos.HeadObjectV2Input{
+			Bucket: s.bucket,
+			Key:    key,
+		})
+		return err
 	})
 	if err != nil {
 		if tosErr, ok := err.(*tos.TosServerError); ok && tosErr.StatusCode == 404 {
@@ -79,10 +380,94 @@ func (s *VolcengineTOSStorage) Exists(key string) (bool, error) {
 	return true, nil
 }
 
+// Presign returns a time-limited URL for GET or PUT access to key without
+// going through the app server. See PresignWithOptions to bind a content
+// type or override response headers on a presigned GET.
+func (s *VolcengineTOSStorage) Presign(key string, method oss.HTTPMethod, expiry time.Duration) (string, error) {
+	return s.PresignWithOptions(key, method, expiry, oss.PresignOptions{})
+}
+
+// PresignWithOptions behaves like Presign but additionally lets the caller
+// bind a content type (PUT) or override response headers (GET).
+func (s *VolcengineTOSStorage) PresignWithOptions(key string, method oss.HTTPMethod, expiry time.Duration, opts oss.PresignOptions) (string, error) {
+	return s.PresignWithOptionsContext(context.Background(), key, method, expiry, opts)
+}
+
+// PresignWithOptionsContext behaves like PresignWithOptions but lets the
+// caller bound the request with ctx and retries transient failures
+// (throttling, 5xx) per the backend's retry policy.
+func (s *VolcengineTOSStorage) PresignWithOptionsContext(ctx context.Context, key string, method oss.HTTPMethod, expiry time.Duration, opts oss.PresignOptions) (string, error) {
+	input, err := buildPreSignedURLInput(s.bucket, key, method, expiry, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var url string
+	err = oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		resp, err := s.client.PreSignedURL(input)
+		if err != nil {
+			return err
+		}
+		url = resp.SignedUrl
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+// buildPreSignedURLInput maps the backend-agnostic method/expiry/
+// oss.PresignOptions onto a tos.PreSignedURLInput.
+func buildPreSignedURLInput(bucket, key string, method oss.HTTPMethod, expiry time.Duration, opts oss.PresignOptions) (*tos.PreSignedURLInput, error) {
+	var httpMethod tos.HttpMethodType
+	switch method {
+	case oss.HTTPMethodGet:
+		httpMethod = tos.HttpMethodGet
+	case oss.HTTPMethodPut:
+		httpMethod = tos.HttpMethodPut
+	default:
+		return nil, oss.ErrArgumentInvalid.WithDetail("unsupported presign method: " + string(method))
+	}
+
+	input := &tos.PreSignedURLInput{
+		HTTPMethod: httpMethod,
+		Bucket:     bucket,
+		Key:        key,
+		Expires:    int64(expiry.Seconds()),
+	}
+	if method == oss.HTTPMethodPut && opts.ContentType != "" {
+		input.Header = map[string]string{"Content-Type": opts.ContentType}
+	}
+	if opts.ResponseContentType != "" || opts.ResponseContentDisposition != "" {
+		query := map[string]string{}
+		if opts.ResponseContentType != "" {
+			query["response-content-type"] = opts.ResponseContentType
+		}
+		if opts.ResponseContentDisposition != "" {
+			query["response-content-disposition"] = opts.ResponseContentDisposition
+		}
+		input.Query = query
+	}
+	return input, nil
+}
+
 func (s *VolcengineTOSStorage) State(key string) (oss.OSSState, error) {
-	resp, err := s.client.HeadObjectV2(context.Background(), &tos.HeadObjectV2Input{
-		Bucket: s.bucket,
-		Key:    key,
+	return s.StateContext(context.Background(), key)
+}
+
+// StateContext behaves like State but lets the caller bound the request
+// with ctx and retries transient failures (throttling, 5xx) per the
+// backend's retry policy.
+func (s *VolcengineTOSStorage) StateContext(ctx context.Context, key string) (oss.OSSState, error) {
+	var resp *tos.HeadObjectV2Output
+	err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		var headErr error
+		resp, headErr = s.client.HeadObjectV2(ctx, &tos.HeadObjectV2Input{
+			Bucket: s.bucket,
+			Key:    key,
+		})
+		return headErr
 	})
 	if err != nil {
 		return oss.OSSState{}, err
@@ -94,6 +479,13 @@ func (s *VolcengineTOSStorage) State(key string) (oss.OSSState, error) {
 }
 
 func (s *VolcengineTOSStorage) List(prefix string) ([]oss.OSSPath, error) {
+	return s.ListContext(context.Background(), prefix)
+}
+
+// ListContext behaves like List but lets the caller bound the request with
+// ctx and retries each page's transient failures (throttling, 5xx) per the
+// backend's retry policy.
+func (s *VolcengineTOSStorage) ListContext(ctx context.Context, prefix string) ([]oss.OSSPath, error) {
 	if !strings.HasSuffix(prefix, "/") {
 		prefix = prefix + "/"
 	}
@@ -101,12 +493,16 @@ func (s *VolcengineTOSStorage) List(prefix string) ([]oss.OSSPath, error) {
 	truncated := true
 	continuationToken := ""
 	for truncated {
-
-		resp, err := s.client.ListObjectsType2(context.Background(), &tos.ListObjectsType2Input{
-			Bucket:            s.bucket,
-			Prefix:            prefix,
-			MaxKeys:           1000,
-			ContinuationToken: continuationToken,
+		var resp *tos.ListObjectsType2Output
+		err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+			var listErr error
+			resp, listErr = s.client.ListObjectsType2(ctx, &tos.ListObjectsType2Input{
+				Bucket:            s.bucket,
+				Prefix:            prefix,
+				MaxKeys:           1000,
+				ContinuationToken: continuationToken,
+			})
+			return listErr
 		})
 		if err != nil {
 			return nil, err
@@ -134,11 +530,136 @@ func (s *VolcengineTOSStorage) List(prefix string) ([]oss.OSSPath, error) {
 }
 
 func (s *VolcengineTOSStorage) Delete(key string) error {
-	_, err := s.client.DeleteObjectV2(context.Background(), &tos.DeleteObjectV2Input{
-		Bucket: s.bucket,
-		Key:    key,
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext behaves like Delete but lets the caller bound the request
+// with ctx and retries transient failures (throttling, 5xx) per the
+// backend's retry policy.
+func (s *VolcengineTOSStorage) DeleteContext(ctx context.Context, key string) error {
+	return oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+		_, err := s.client.DeleteObjectV2(ctx, &tos.DeleteObjectV2Input{
+			Bucket: s.bucket,
+			Key:    key,
+		})
+		return err
 	})
-	return err
+}
+
+// DeleteMany deletes all of keys in batches of up to 1000 using
+// TOS's DeleteMultiObjectsV2 API, returning a per-key error for any object
+// that failed to delete.
+func (s *VolcengineTOSStorage) DeleteMany(keys []string) (map[string]error, error) {
+	return s.DeleteManyContext(context.Background(), keys)
+}
+
+// DeleteManyContext behaves like DeleteMany but lets the caller bound the
+// request with ctx and retries a batch's DeleteMultiObjectsV2 call on
+// transient failures (throttling, 5xx) per the backend's retry policy.
+func (s *VolcengineTOSStorage) DeleteManyContext(ctx context.Context, keys []string) (map[string]error, error) {
+	result := make(map[string]error)
+
+	for _, input := range buildDeleteMultiObjectsBatches(s.bucket, keys) {
+		var resp *tos.DeleteMultiObjectsV2Output
+		err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+			var deleteErr error
+			resp, deleteErr = s.client.DeleteMultiObjectsV2(ctx, input)
+			return deleteErr
+		})
+		if err != nil {
+			return result, err
+		}
+		for _, objErr := range resp.Errors {
+			result[objErr.Key] = errors.New(objErr.Message)
+		}
+	}
+
+	return result, nil
+}
+
+// buildDeleteMultiObjectsBatches splits keys into groups of at most 1000 (the
+// limit DeleteMultiObjectsV2 accepts in a single call) and builds the
+// tos.DeleteMultiObjectsV2Input for each group.
+func buildDeleteMultiObjectsBatches(bucket string, keys []string) []*tos.DeleteMultiObjectsV2Input {
+	const batchSize = 1000
+
+	var batches []*tos.DeleteMultiObjectsV2Input
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := keys[start:end]
+
+		objects := make([]tos.ObjectTobeDeleted, len(batch))
+		for i, key := range batch {
+			objects[i] = tos.ObjectTobeDeleted{Key: key}
+		}
+
+		batches = append(batches, &tos.DeleteMultiObjectsV2Input{
+			Bucket:  bucket,
+			Objects: objects,
+			Quiet:   true,
+		})
+	}
+
+	return batches
+}
+
+// DeletePrefix removes every object whose key starts with prefix.
+func (s *VolcengineTOSStorage) DeletePrefix(prefix string) error {
+	return s.DeletePrefixContext(context.Background(), prefix)
+}
+
+// DeletePrefixContext behaves like DeletePrefix but lets the caller bound
+// the request with ctx.
+func (s *VolcengineTOSStorage) DeletePrefixContext(ctx context.Context, prefix string) error {
+	var keys []string
+	truncated := true
+	continuationToken := ""
+	for truncated {
+		var resp *tos.ListObjectsType2Output
+		err := oss.Retry(ctx, s.retryPolicy, isRetryableError, func() error {
+			var listErr error
+			resp, listErr = s.client.ListObjectsType2(ctx, &tos.ListObjectsType2Input{
+				Bucket:            s.bucket,
+				Prefix:            prefix,
+				MaxKeys:           1000,
+				ContinuationToken: continuationToken,
+			})
+			return listErr
+		})
+		if err != nil {
+			return err
+		}
+		for _, obj := range resp.Contents {
+			keys = append(keys, obj.Key)
+		}
+		if !resp.IsTruncated {
+			break
+		}
+		truncated = resp.IsTruncated
+		continuationToken = resp.NextContinuationToken
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	deleteErrs, err := s.DeleteManyContext(ctx, keys)
+	if err != nil {
+		return err
+	}
+	if len(deleteErrs) == 0 {
+		return nil
+	}
+
+	failed := make([]string, 0, len(deleteErrs))
+	for key, deleteErr := range deleteErrs {
+		failed = append(failed, key+": "+deleteErr.Error())
+	}
+	sort.Strings(failed)
+	return fmt.Errorf("failed to delete %d of %d objects under prefix %q: %s", len(deleteErrs), len(keys), prefix, strings.Join(failed, "; "))
 }
 
 func (s *VolcengineTOSStorage) Type() string {
@@ -0,0 +1,165 @@
+package volcenginetos
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/langgenius/dify-cloud-kit/oss"
+	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/ve-tos-golang-sdk/v2/tos"
+)
+
+func TestApplyPutOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts oss.PutOptions
+		want *tos.PutObjectBasicInput
+	}{
+		{
+			name: "empty options leave the input untouched",
+			opts: oss.PutOptions{},
+			want: &tos.PutObjectBasicInput{},
+		},
+		{
+			name: "content type, cache control and metadata",
+			opts: oss.PutOptions{
+				ContentType:  "application/json",
+				CacheControl: "max-age=3600",
+				Metadata:     map[string]string{"owner": "dify"},
+			},
+			want: &tos.PutObjectBasicInput{
+				ContentType:  "application/json",
+				CacheControl: "max-age=3600",
+				Meta:         map[string]string{"owner": "dify"},
+			},
+		},
+		{
+			name: "SSE-KMS",
+			opts: oss.PutOptions{
+				ServerSideEncryption: "kms",
+				EncryptionKeyID:      "example-kms-key-id",
+			},
+			want: &tos.PutObjectBasicInput{
+				ServerSideEncryption: "kms",
+				SSEKMSKeyID:          "example-kms-key-id",
+			},
+		},
+		{
+			name: "SSE-C",
+			opts: oss.PutOptions{
+				SSECustomerAlgorithm: "AES256",
+				SSECustomerKey:       "base64-key",
+				SSECustomerKeyMD5:    "base64-md5",
+			},
+			want: &tos.PutObjectBasicInput{
+				SSECAlgorithm: "AES256",
+				SSECKey:       "base64-key",
+				SSECKeyMD5:    "base64-md5",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &tos.PutObjectBasicInput{}
+			applyPutOptions(input, tt.opts)
+			assert.Equal(t, tt.want, input)
+		})
+	}
+}
+
+func TestBuildPreSignedURLInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  oss.HTTPMethod
+		opts    oss.PresignOptions
+		want    *tos.PreSignedURLInput
+		wantErr bool
+	}{
+		{
+			name:   "GET with no response overrides",
+			method: oss.HTTPMethodGet,
+			want: &tos.PreSignedURLInput{
+				HTTPMethod: tos.HttpMethodGet,
+				Bucket:     "bucket",
+				Key:        "key",
+				Expires:    60,
+			},
+		},
+		{
+			name:   "GET with response overrides",
+			method: oss.HTTPMethodGet,
+			opts: oss.PresignOptions{
+				ResponseContentType:        "application/pdf",
+				ResponseContentDisposition: "attachment; filename=report.pdf",
+			},
+			want: &tos.PreSignedURLInput{
+				HTTPMethod: tos.HttpMethodGet,
+				Bucket:     "bucket",
+				Key:        "key",
+				Expires:    60,
+				Query: map[string]string{
+					"response-content-type":        "application/pdf",
+					"response-content-disposition": "attachment; filename=report.pdf",
+				},
+			},
+		},
+		{
+			name:   "PUT",
+			method: oss.HTTPMethodPut,
+			want: &tos.PreSignedURLInput{
+				HTTPMethod: tos.HttpMethodPut,
+				Bucket:     "bucket",
+				Key:        "key",
+				Expires:    60,
+			},
+		},
+		{
+			name:    "unsupported method",
+			method:  oss.HTTPMethod("HEAD"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildPreSignedURLInput("bucket", "key", tt.method, time.Minute, tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildDeleteMultiObjectsBatches(t *testing.T) {
+	t.Run("no keys produces no batches", func(t *testing.T) {
+		got := buildDeleteMultiObjectsBatches("bucket", nil)
+		assert.Nil(t, got)
+	})
+
+	t.Run("fewer than 1000 keys produces a single batch", func(t *testing.T) {
+		keys := []string{"a", "b", "c"}
+		got := buildDeleteMultiObjectsBatches("bucket", keys)
+		assert.Len(t, got, 1)
+		assert.Equal(t, "bucket", got[0].Bucket)
+		assert.True(t, got[0].Quiet)
+		assert.Equal(t, []tos.ObjectTobeDeleted{{Key: "a"}, {Key: "b"}, {Key: "c"}}, got[0].Objects)
+	})
+
+	t.Run("more than 1000 keys splits into multiple batches", func(t *testing.T) {
+		keys := make([]string, 1500)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("key-%d", i)
+		}
+		got := buildDeleteMultiObjectsBatches("bucket", keys)
+		assert.Len(t, got, 2)
+		assert.Len(t, got[0].Objects, 1000)
+		assert.Len(t, got[1].Objects, 500)
+		assert.Equal(t, "key-0", got[0].Objects[0].Key)
+		assert.Equal(t, "key-1000", got[1].Objects[0].Key)
+	})
+}
@@ -187,3 +187,49 @@ func TestAll(t *testing.T) {
 		assert.Nil(t, err, info)
 	}
 }
+
+// TestWithPrefix checks that oss.WithPrefix transparently roots a backend
+// under a tenant prefix without changing the observable behavior the rest
+// of TestAll relies on.
+func TestWithPrefix(t *testing.T) {
+	inner, err := factory.Load("local", oss.OSSArgs{
+		Local: &oss.Local{
+			Path: "/tmp/dify-oss-tests-prefixed",
+		},
+	})
+	assert.Nil(t, err)
+
+	storage := oss.WithPrefix(inner, "tenant-a")
+
+	prefix := randomString(5)
+	key := fmt.Sprintf("%s/%s", prefix, randomString(10))
+	data := make([]byte, 1*1024*1024)
+
+	exist, err := storage.Exists(key)
+	assert.Equal(t, false, exist)
+	assert.Nil(t, err)
+
+	err = storage.Save(key, data)
+	assert.Nil(t, err)
+
+	rdata, err := storage.Load(key)
+	assert.Equal(t, data, rdata)
+	assert.Nil(t, err)
+
+	ossPaths, err := storage.List(prefix)
+	assert.Equal(t, 1, len(ossPaths))
+	assert.Nil(t, err)
+
+	// the key is only visible to the tenant-a view; the underlying backend
+	// stores it under the prefixed path instead.
+	rawExist, err := inner.Exists(fmt.Sprintf("tenant-a/%s", key))
+	assert.Equal(t, true, rawExist)
+	assert.Nil(t, err)
+
+	err = storage.Delete(key)
+	assert.Nil(t, err)
+
+	exist, err = storage.Exists(key)
+	assert.Equal(t, false, exist)
+	assert.Nil(t, err)
+}